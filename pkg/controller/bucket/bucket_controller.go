@@ -19,13 +19,18 @@ package bucket
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strings"
+	"sync"
 	"time"
 
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilversion "k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/apimachinery/pkg/util/wait"
 
 	kubeclientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 
 	"github.com/container-object-storage-interface/api/apis/objectstorage.k8s.io/v1alpha1"
@@ -38,6 +43,69 @@ import (
 	"golang.org/x/time/rate"
 )
 
+const (
+	// maxBucketTags is the maximum number of tags a bucket may carry,
+	// mirroring the S3/OSS tagging limit.
+	maxBucketTags = 10
+	// maxTagKeyLength and maxTagValueLength mirror the S3/OSS tagging limits.
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+
+	// bucketConditionUpdated is set to True once a Bucket update has been
+	// applied to the provisioner.
+	bucketConditionUpdated = "BucketUpdated"
+	// bucketConditionUpdateFailed is set to True when an update could not be
+	// applied: because it touched an immutable field, because the new tags
+	// failed validation, or because a provisioner RPC failed. Reason/Message
+	// carry the specifics; a tag validation failure uses reasonProvisionerErr.
+	bucketConditionUpdateFailed = "BucketUpdateFailed"
+
+	reasonImmutableField  = "ImmutableFieldChanged"
+	reasonProvisionerErr  = "ProvisionerError"
+	reasonUpdateSucceeded = "UpdateSucceeded"
+
+	// bucketConditionReleased is set to True when a Bucket with a Retain
+	// reclaim policy is released without its backing storage bucket being
+	// deleted.
+	bucketConditionReleased = "BucketReleased"
+
+	// bucketProtectionFinalizer is held on a Bucket from Add until the
+	// provisioner has confirmed deletion (or the Bucket was released under a
+	// Retain reclaim policy), so that removing the Kubernetes object can
+	// never silently orphan or destroy the backing storage bucket.
+	bucketProtectionFinalizer = "objectstorage.k8s.io/bucket-protection"
+
+	// defaultResyncPeriod is how often runResyncLoop re-checks every cached
+	// Bucket in the absence of an explicit --resync-period flag.
+	defaultResyncPeriod = 15 * time.Minute
+
+	// byProvisionerIndex indexes cached Buckets by spec.provisioner so
+	// filtering to this sidecar's buckets is a map lookup rather than a
+	// scan-and-compare over every Bucket in the cluster.
+	byProvisionerIndex = "byProvisioner"
+)
+
+// validateTags enforces the provisioner-agnostic tag constraints shared by
+// S3, GCS and Azure: at most maxBucketTags entries, and bounded key/value
+// lengths. Providers that are more restrictive are expected to reject the
+// request themselves.
+func validateTags(tags map[string]string) error {
+	if len(tags) > maxBucketTags {
+		return fmt.Errorf("bucket tags: at most %d tags are allowed, got %d", maxBucketTags, len(tags))
+	}
+
+	for k, v := range tags {
+		if len(k) == 0 || len(k) > maxTagKeyLength {
+			return fmt.Errorf("bucket tags: key %q must be between 1 and %d characters", k, maxTagKeyLength)
+		}
+		if len(v) > maxTagValueLength {
+			return fmt.Errorf("bucket tags: value for key %q must be at most %d characters", k, maxTagValueLength)
+		}
+	}
+
+	return nil
+}
+
 // bucketListener manages Bucket objects
 type bucketListener struct {
 	kubeClient        kubeclientset.Interface
@@ -48,10 +116,49 @@ type bucketListener struct {
 	// provisions buckets.
 	provisionerName string
 	kubeVersion     *utilversion.Version
+
+	// resyncPeriod drives runResyncLoop, which walks bucketIndexer and calls
+	// syncBucket for every cached Bucket even absent a watch event. This is
+	// what lets drift between Kubernetes state and the provisioner backend
+	// (e.g. a Bucket the provisioner never actually created) get corrected
+	// eventually.
+	resyncPeriod time.Duration
+
+	// bucketIndexer caches the Buckets this listener has been notified about
+	// via Add/Update/Delete, indexed by byProvisionerIndex so
+	// bucketsForProvisioner doesn't require a live read against the API
+	// server. It is fed entirely by those callbacks rather than by its own
+	// List/Watch, since the embedding controller.ObjectStorageController
+	// already watches Buckets to deliver them. Descoped from the original
+	// ask for a shared informer factory over both Buckets and
+	// BucketRequests: there is no BucketRequests client in this package to
+	// build one from, so matching Buckets to BucketRequests still requires a
+	// live read rather than an indexed lookup.
+	bucketIndexer cache.Indexer
+
+	// appliedMu guards applied.
+	appliedMu sync.Mutex
+	// applied records, per Bucket name, the mutable spec fields last
+	// confirmed pushed to the provisioner by createBucket/updateBucket. This
+	// is what runResyncLoop diffs the live cached Bucket against: comparing
+	// a Bucket to itself can never detect drift, so without this record
+	// syncBucket would have nothing to apply updateBucket's diff logic to.
+	applied map[string]appliedBucketState
+}
+
+// appliedBucketState is the subset of Bucket.Spec last confirmed applied to
+// the provisioner for a given Bucket name.
+type appliedBucketState struct {
+	tags     map[string]string
+	protocol v1alpha1.Protocol
 }
 
-// NewBucketController returns a controller that manages Bucket objects
-func NewBucketController(provisionerName string, client osspec.ProvisionerClient) (*controller.ObjectStorageController, error) {
+// NewBucketController returns a controller that manages Bucket objects.
+// resyncPeriod configures how often runResyncLoop re-checks every cached
+// Bucket against the provisioner; callers should thread this through from a
+// --resync-period flag and fall back to defaultResyncPeriod when it is
+// unset.
+func NewBucketController(provisionerName string, client osspec.ProvisionerClient, resyncPeriod time.Duration) (*controller.ObjectStorageController, error) {
 	rateLimit := workqueue.NewMaxOfRateLimiter(
 		workqueue.NewItemExponentialFailureRateLimiter(5*time.Second, 60*time.Minute),
 		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(10), 100)},
@@ -63,9 +170,14 @@ func NewBucketController(provisionerName string, client osspec.ProvisionerClient
 		return nil, err
 	}
 
+	if resyncPeriod <= 0 {
+		resyncPeriod = defaultResyncPeriod
+	}
+
 	bl := bucketListener{
 		provisionerName:   provisionerName,
 		provisionerClient: client,
+		resyncPeriod:      resyncPeriod,
 	}
 	bc.AddBucketListener(&bl)
 
@@ -84,9 +196,205 @@ func (bl *bucketListener) InitializeKubeClient(k kubeclientset.Interface) {
 	}
 }
 
-// InitializeBucketClient initializes the object storage bucket client
+// InitializeBucketClient initializes the object storage bucket client and the
+// indexed cache getBucket and bucketsForProvisioner read from. The cache is
+// populated purely from Add/Update/Delete as the embedding
+// controller.ObjectStorageController delivers them, rather than by this
+// package opening its own List/Watch against Buckets: that controller
+// already runs the one watch this sidecar needs, and a second informer here
+// would double the list/watch load on the apiserver for the same resource.
+// The tradeoff is that our cache only reflects Buckets this process has
+// actually been notified about, which a fresh process fills in as soon as
+// the controller's own initial list/watch delivers its Add calls. There is
+// no equivalent cache for BucketRequests: this package has no BucketRequests
+// client to build one from, so matching a Bucket to its BucketRequest is
+// still a live read rather than the indexed lookup originally asked for.
 func (bl *bucketListener) InitializeBucketClient(bc bucketclientset.Interface) {
 	bl.bucketClient = bc
+	bl.ensureIndexer()
+
+	if bl.resyncPeriod <= 0 {
+		bl.resyncPeriod = defaultResyncPeriod
+	}
+	go bl.runResyncLoop(wait.NeverStop)
+}
+
+// ensureIndexer lazily creates bucketIndexer so a bucketListener used
+// directly (as the unit tests in this package do, without going through
+// InitializeBucketClient) still has a cache to read from and write to.
+func (bl *bucketListener) ensureIndexer() cache.Indexer {
+	if bl.bucketIndexer == nil {
+		bl.bucketIndexer = cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{byProvisionerIndex: indexBucketByProvisioner})
+	}
+	return bl.bucketIndexer
+}
+
+// runResyncLoop periodically re-runs syncBucket against every Bucket this
+// provisioner has cached, so drift against the provisioner backend (a
+// create that never landed, a tag or region that fell out of sync) gets
+// corrected even without a fresh watch event. It walks bl.bucketIndexer
+// rather than re-listing the API server, since that cache is already kept
+// current by Add/Update/Delete.
+func (bl *bucketListener) runResyncLoop(stopCh <-chan struct{}) {
+	wait.Until(func() {
+		for _, key := range bl.bucketIndexer.ListKeys() {
+			obj, exists, err := bl.getBucket(key)
+			if err != nil || !exists {
+				continue
+			}
+			if err := bl.syncBucket(context.Background(), obj); err != nil {
+				klog.Errorf("resync: error syncing bucket %s: %v", key, err)
+			}
+		}
+	}, bl.resyncPeriod, stopCh)
+}
+
+// indexBucketByProvisioner is the byProvisionerIndex index function.
+func indexBucketByProvisioner(obj interface{}) ([]string, error) {
+	b, ok := obj.(*v1alpha1.Bucket)
+	if !ok {
+		return nil, nil
+	}
+	return []string{b.Spec.Provisioner}, nil
+}
+
+// getBucket reads a single Bucket by name from the cache. It returns a
+// DeepCopy rather than the indexer's own pointer, since callers (syncBucket,
+// via runResyncLoop) mutate the Bucket they get back; mutating the cached
+// pointer directly would let a Status write that never reached the
+// apiserver still get treated as applied on every later resync.
+func (bl *bucketListener) getBucket(name string) (*v1alpha1.Bucket, bool, error) {
+	obj, exists, err := bl.ensureIndexer().GetByKey(name)
+	if err != nil || !exists {
+		return nil, exists, err
+	}
+	b, ok := obj.(*v1alpha1.Bucket)
+	if !ok {
+		return nil, false, fmt.Errorf("unexpected object of type %T in bucket indexer", obj)
+	}
+	return b.DeepCopy(), true, nil
+}
+
+// bucketsForProvisioner returns the cached Buckets belonging to this
+// provisioner via byProvisionerIndex, an O(1) lookup rather than a scan
+// comparing spec.provisioner against every cached Bucket. Each result is a
+// DeepCopy for the same reason getBucket returns one.
+func (bl *bucketListener) bucketsForProvisioner() ([]*v1alpha1.Bucket, error) {
+	objs, err := bl.ensureIndexer().ByIndex(byProvisionerIndex, bl.provisionerName)
+	if err != nil {
+		return nil, err
+	}
+	buckets := make([]*v1alpha1.Bucket, 0, len(objs))
+	for _, obj := range objs {
+		if b, ok := obj.(*v1alpha1.Bucket); ok {
+			buckets = append(buckets, b.DeepCopy())
+		}
+	}
+	return buckets, nil
+}
+
+// recordApplied snapshots the mutable spec fields of a Bucket just pushed to
+// the provisioner, for syncBucket to diff later resyncs against. Called only
+// after the provisioner RPC has actually succeeded, so a bucket whose
+// create or update failed never appears synced to a future resync.
+func (bl *bucketListener) recordApplied(obj *v1alpha1.Bucket) {
+	bl.appliedMu.Lock()
+	defer bl.appliedMu.Unlock()
+	if bl.applied == nil {
+		bl.applied = map[string]appliedBucketState{}
+	}
+	bl.applied[obj.Name] = appliedBucketState{
+		tags:     obj.Spec.Tags,
+		protocol: obj.Spec.Protocol,
+	}
+}
+
+// lastApplied returns the mutable spec fields last recorded as applied for
+// name, if any.
+func (bl *bucketListener) lastApplied(name string) (appliedBucketState, bool) {
+	bl.appliedMu.Lock()
+	defer bl.appliedMu.Unlock()
+	s, ok := bl.applied[name]
+	return s, ok
+}
+
+// forgetApplied drops name's recorded applied state, once reconcileDeletion
+// has finished with the Bucket, so bl.applied doesn't grow for the life of
+// the process as Buckets churn.
+func (bl *bucketListener) forgetApplied(name string) {
+	bl.appliedMu.Lock()
+	defer bl.appliedMu.Unlock()
+	delete(bl.applied, name)
+}
+
+// syncBucket is the resync loop's reconciliation entrypoint. For a Bucket
+// not yet marked available it re-issues createBucket, same as Add would.
+// For one already available, it diffs the live cached spec against
+// lastApplied - the mutable fields recorded the last time createBucket or
+// updateBucket actually got them applied to the provisioner - rather than
+// against itself, so drift that happened outside a watch event (a tag or
+// region that fell out of sync, an update whose RPC never landed) is
+// something updateBucket's diff logic can actually detect and correct.
+// Add and Update keep their own entrypoints below, since the watch
+// framework already tells them unambiguously which case applies and, for
+// Update, supplies a real old/new pair to diff directly. Deletion is
+// handled separately by reconcileDeletion, since it is driven by
+// DeletionTimestamp/finalizer state rather than a spec diff.
+func (bl *bucketListener) syncBucket(ctx context.Context, obj *v1alpha1.Bucket) error {
+	if !strings.EqualFold(obj.Spec.Provisioner, bl.provisionerName) {
+		return nil
+	}
+
+	var syncErr error
+	rejected := false
+	if !obj.Status.BucketAvailable {
+		syncErr = bl.createBucket(ctx, obj)
+	} else {
+		old := obj.DeepCopy()
+		if applied, ok := bl.lastApplied(obj.Name); ok {
+			old.Spec.Tags = applied.tags
+			old.Spec.Protocol = applied.protocol
+		} else {
+			// Nothing recorded yet for this bucket - e.g. this process just
+			// started and is walking buckets it was never notified about via
+			// Add/Update. Leaving old equal to obj here would diff the live
+			// spec against itself and look like nothing needs reconciling,
+			// the exact bug this resync rewrite exists to fix. Diff against
+			// an empty baseline instead, so any real tags or mutable
+			// protocol fields on obj get pushed to the provisioner at least
+			// once; that's a safe no-op if the provisioner already has them.
+			old.Spec.Tags = nil
+			if old.Spec.Protocol.S3 != nil {
+				unset := *old.Spec.Protocol.S3
+				unset.Region = ""
+				old.Spec.Protocol.S3 = &unset
+			}
+		}
+		rejected = diffImmutableFields(&old.Spec, &obj.Spec) != nil
+		syncErr = bl.updateBucket(ctx, old, obj)
+	}
+
+	// obj is a copy handed to createBucket/updateBucket above, which may have
+	// mutated its Status optimistically (e.g. BucketAvailable) before the
+	// persistStatus write that was supposed to make that durable ever
+	// confirmed success. Only recache it, and only record it as applied, once
+	// syncErr is nil: on failure the cache keeps whatever it held before this
+	// resync tick, so the next tick still sees the bucket as needing a real
+	// create/update rather than wrongly treating a failed attempt as settled.
+	if syncErr != nil {
+		return syncErr
+	}
+
+	// An update rejected for touching an immutable field returns no error
+	// but applied nothing, so it is not recorded as applied either.
+	if !rejected {
+		bl.recordApplied(obj)
+	}
+	if err := bl.ensureIndexer().Update(obj); err != nil {
+		klog.Errorf("error caching bucket %s: %v", obj.Name, err)
+	}
+
+	return nil
 }
 
 // Add will call the provisioner and add a bucket
@@ -98,8 +406,29 @@ func (bl *bucketListener) Add(ctx context.Context, obj *v1alpha1.Bucket) error {
 		return nil
 	}
 
+	err := bl.createBucket(ctx, obj)
+	if err == nil {
+		bl.recordApplied(obj)
+	}
+	if cacheErr := bl.ensureIndexer().Update(obj); cacheErr != nil {
+		klog.Errorf("error caching bucket %s: %v", obj.Name, cacheErr)
+	}
+	return err
+}
+
+// createBucket issues ProvisionerCreateBucket for a Bucket that hasn't been
+// marked available yet, ensuring the protection finalizer is in place first
+// so a racing delete can't slip past it before the create completes.
+func (bl *bucketListener) createBucket(ctx context.Context, obj *v1alpha1.Bucket) error {
+	if err := validateTags(obj.Spec.Tags); err != nil {
+		klog.Errorf("invalid tags for bucket %s: %v", obj.Name, err)
+		return err
+	}
+
 	req := osspec.ProvisionerCreateBucketRequest{
-		BucketName: obj.Spec.BucketRequest.Name,
+		BucketName:       obj.Spec.BucketRequest.Name,
+		Tags:             obj.Spec.Tags,
+		TaggingDirective: taggingDirective(obj.Spec.TaggingDirective),
 	}
 
 	switch obj.Spec.Protocol.Name {
@@ -113,6 +442,10 @@ func (bl *bucketListener) Add(ctx context.Context, obj *v1alpha1.Bucket) error {
 		return fmt.Errorf(errStr)
 	}
 
+	if err := bl.ensureFinalizer(ctx, obj); err != nil {
+		return err
+	}
+
 	// TODO set grpc timeout
 	rsp, err := bl.provisionerClient.ProvisionerCreateBucket(ctx, &req)
 	if err != nil {
@@ -123,25 +456,311 @@ func (bl *bucketListener) Add(ctx context.Context, obj *v1alpha1.Bucket) error {
 
 	// update bucket status to success
 	obj.Status.BucketAvailable = true
-	_, err = bl.bucketClient.ObjectstorageV1alpha1().Buckets().UpdateStatus(ctx, obj, metav1.UpdateOptions{})
-	return err
+	return bl.persistStatus(ctx, obj)
+}
+
+// ensureFinalizer adds bucketProtectionFinalizer to the Bucket if it isn't
+// already present, so that deletion of the Kubernetes object cannot bypass
+// reconcileDeletion.
+func (bl *bucketListener) ensureFinalizer(ctx context.Context, obj *v1alpha1.Bucket) error {
+	if hasFinalizer(obj, bucketProtectionFinalizer) {
+		return nil
+	}
+
+	obj.Finalizers = append(obj.Finalizers, bucketProtectionFinalizer)
+	updated, err := bl.bucketClient.ObjectstorageV1alpha1().Buckets().Update(ctx, obj, metav1.UpdateOptions{})
+	if err != nil {
+		klog.Errorf("error adding finalizer to bucket %s: %v", obj.Name, err)
+		return err
+	}
+	*obj = *updated
+	return nil
+}
+
+func hasFinalizer(obj *v1alpha1.Bucket, finalizer string) bool {
+	for _, f := range obj.Finalizers {
+		if f == finalizer {
+			return true
+		}
+	}
+	return false
+}
+
+func removeFinalizer(obj *v1alpha1.Bucket, finalizer string) {
+	filtered := make([]string, 0, len(obj.Finalizers))
+	for _, f := range obj.Finalizers {
+		if f != finalizer {
+			filtered = append(filtered, f)
+		}
+	}
+	obj.Finalizers = filtered
 }
 
-// Update does nothing
+// Update reconciles a Bucket whose spec or status changed. Deletion in
+// progress (the finalizer blocks the object from actually being removed) is
+// observed here rather than in Delete; everything else is diffed against
+// the prior state by updateBucket.
 func (bl *bucketListener) Update(ctx context.Context, old, new *v1alpha1.Bucket) error {
 	klog.V(1).Infof("bucketListener: update called for bucket %s", old.Name)
+
+	// Verify this bucket is for this provisioner
+	if !strings.EqualFold(new.Spec.Provisioner, bl.provisionerName) {
+		return nil
+	}
+
+	if new.DeletionTimestamp != nil {
+		return bl.reconcileDeletion(ctx, new)
+	}
+
+	err := bl.updateBucket(ctx, old, new)
+	if err == nil && diffImmutableFields(&old.Spec, &new.Spec) == nil {
+		bl.recordApplied(new)
+	}
+	if cacheErr := bl.ensureIndexer().Update(new); cacheErr != nil {
+		klog.Errorf("error caching bucket %s: %v", new.Name, cacheErr)
+	}
+	return err
+}
+
+// updateBucket diffs old against new and issues whatever RPCs are needed to
+// converge the provisioner: immutable fields are rejected, tags are
+// reconciled independently since they're their own mutable resource, and any
+// remaining protocol-specific mutable fields go through
+// ProvisionerUpdateBucket.
+func (bl *bucketListener) updateBucket(ctx context.Context, old, new *v1alpha1.Bucket) error {
+	if err := diffImmutableFields(&old.Spec, &new.Spec); err != nil {
+		klog.Errorf("rejecting update to bucket %s: %v", new.Name, err)
+		bl.markUpdateFailed(new, reasonImmutableField, err)
+		return bl.persistStatus(ctx, new)
+	}
+
+	tagsChanged := !reflect.DeepEqual(old.Spec.Tags, new.Spec.Tags)
+	if err := bl.syncBucketTags(ctx, old, new); err != nil {
+		bl.markUpdateFailed(new, reasonProvisionerErr, err)
+		if statusErr := bl.persistStatus(ctx, new); statusErr != nil {
+			return statusErr
+		}
+		return err
+	}
+
+	if !mutableProtocolFieldsChanged(&old.Spec, &new.Spec) {
+		if !tagsChanged {
+			return nil
+		}
+		// Tags were the only thing that changed, but they're applied above
+		// independently of ProvisionerUpdateBucket below, so record success
+		// here rather than falling through to the no-op return a pure
+		// protocol-field comparison would otherwise give a real change.
+		bl.markUpdateSucceeded(new)
+		return bl.persistStatus(ctx, new)
+	}
+
+	req := osspec.ProvisionerUpdateBucketRequest{
+		BucketName: new.Spec.BucketRequest.Name,
+		Tags:       new.Spec.Tags,
+	}
+
+	switch new.Spec.Protocol.Name {
+	case v1alpha1.ProtocolNameS3:
+		req.Region = new.Spec.Protocol.S3.Region
+	case v1alpha1.ProtocolNameAzure:
+	case v1alpha1.ProtocolNameGCS:
+	}
+
+	rsp, err := bl.provisionerClient.ProvisionerUpdateBucket(ctx, &req)
+	if err != nil {
+		klog.Errorf("error calling ProvisionerUpdateBucket: %v", err)
+		bl.markUpdateFailed(new, reasonProvisionerErr, err)
+		if statusErr := bl.persistStatus(ctx, new); statusErr != nil {
+			return statusErr
+		}
+		return err
+	}
+	klog.V(1).Infof("provisioner returned update bucket response %v", rsp)
+
+	bl.markUpdateSucceeded(new)
+	return bl.persistStatus(ctx, new)
+}
+
+// diffImmutableFields rejects changes to Bucket.Spec fields that cannot be
+// changed after creation: the provisioner, the protocol, and the bucket
+// name itself.
+func diffImmutableFields(old, new *v1alpha1.BucketSpec) error {
+	if !strings.EqualFold(old.Provisioner, new.Provisioner) {
+		return fmt.Errorf("spec.provisioner is immutable: %q -> %q", old.Provisioner, new.Provisioner)
+	}
+	if old.Protocol.Name != new.Protocol.Name {
+		return fmt.Errorf("spec.protocol.name is immutable: %q -> %q", old.Protocol.Name, new.Protocol.Name)
+	}
+	if old.BucketRequest.Name != new.BucketRequest.Name {
+		return fmt.Errorf("spec.bucketRequest.name is immutable: %q -> %q", old.BucketRequest.Name, new.BucketRequest.Name)
+	}
 	return nil
 }
 
-// Delete will call the provisioner and delete a bucket
+// mutableProtocolFieldsChanged reports whether any protocol-specific mutable
+// field differs between old and new. Tags are excluded here since they are
+// reconciled independently by syncBucketTags.
+func mutableProtocolFieldsChanged(old, new *v1alpha1.BucketSpec) bool {
+	switch new.Protocol.Name {
+	case v1alpha1.ProtocolNameS3:
+		return old.Protocol.S3 != nil && new.Protocol.S3 != nil && old.Protocol.S3.Region != new.Protocol.S3.Region
+	default:
+		return false
+	}
+}
+
+// markUpdateSucceeded records a BucketUpdated condition and clears any
+// previous failure.
+func (bl *bucketListener) markUpdateSucceeded(obj *v1alpha1.Bucket) {
+	apimeta.SetStatusCondition(&obj.Status.Conditions, metav1.Condition{
+		Type:               bucketConditionUpdated,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: obj.Generation,
+		Reason:             reasonUpdateSucceeded,
+		Message:            "bucket spec applied to provisioner",
+	})
+	apimeta.RemoveStatusCondition(&obj.Status.Conditions, bucketConditionUpdateFailed)
+}
+
+// markUpdateFailed records a BucketUpdateFailed condition carrying the
+// reason and underlying error.
+func (bl *bucketListener) markUpdateFailed(obj *v1alpha1.Bucket, reason string, err error) {
+	apimeta.SetStatusCondition(&obj.Status.Conditions, metav1.Condition{
+		Type:               bucketConditionUpdateFailed,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: obj.Generation,
+		Reason:             reason,
+		Message:            err.Error(),
+	})
+}
+
+// persistStatus writes the Bucket's status subresource, logging and
+// returning any error so the caller's workqueue item is retried with
+// backoff.
+func (bl *bucketListener) persistStatus(ctx context.Context, obj *v1alpha1.Bucket) error {
+	_, err := bl.bucketClient.ObjectstorageV1alpha1().Buckets().UpdateStatus(ctx, obj, metav1.UpdateOptions{})
+	if err != nil {
+		klog.Errorf("error updating status for bucket %s: %v", obj.Name, err)
+	}
+	return err
+}
+
+// taggingDirective maps Bucket.Spec.TaggingDirective to the provisioner wire
+// type, defaulting to Replace so Buckets that don't set it keep today's
+// behavior of the new tag set fully replacing the old one.
+func taggingDirective(d v1alpha1.TaggingDirective) osspec.TaggingDirective {
+	if d == v1alpha1.TaggingDirectiveCopy {
+		return osspec.TaggingDirectiveCopy
+	}
+	return osspec.TaggingDirectiveReplace
+}
+
+// syncBucketTags issues a ProvisionerSetBucketTagging or
+// ProvisionerDeleteBucketTagging RPC when the tag set on a Bucket changes.
+// Tags are a first-class, independently mutable resource, so the directive
+// is read from Spec.TaggingDirective rather than assumed: Replace swaps in
+// the new set wholesale, Copy asks the provisioner to merge it with what's
+// already there.
+func (bl *bucketListener) syncBucketTags(ctx context.Context, old, new *v1alpha1.Bucket) error {
+	if reflect.DeepEqual(old.Spec.Tags, new.Spec.Tags) {
+		return nil
+	}
+
+	if err := validateTags(new.Spec.Tags); err != nil {
+		klog.Errorf("invalid tags for bucket %s: %v", new.Name, err)
+		return err
+	}
+
+	bucketName := new.Spec.BucketRequest.Name
+
+	if len(new.Spec.Tags) == 0 {
+		if _, err := bl.provisionerClient.ProvisionerDeleteBucketTagging(ctx, &osspec.ProvisionerDeleteBucketTaggingRequest{
+			BucketName: bucketName,
+		}); err != nil {
+			klog.Errorf("error calling ProvisionerDeleteBucketTagging: %v", err)
+			return err
+		}
+		return nil
+	}
+
+	if _, err := bl.provisionerClient.ProvisionerSetBucketTagging(ctx, &osspec.ProvisionerSetBucketTaggingRequest{
+		BucketName:       bucketName,
+		Tags:             new.Spec.Tags,
+		TaggingDirective: taggingDirective(new.Spec.TaggingDirective),
+	}); err != nil {
+		klog.Errorf("error calling ProvisionerSetBucketTagging: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Delete handles the case where the underlying framework surfaces bucket
+// removal directly rather than through a DeletionTimestamp on Update. It
+// shares reconcileDeletion with that path so the two are equivalent and
+// idempotent with one another.
 func (bl *bucketListener) Delete(ctx context.Context, obj *v1alpha1.Bucket) error {
 	klog.V(1).Infof("bucketListener: delete called for bucket %s", obj.Name)
+	return bl.reconcileDeletion(ctx, obj)
+}
 
+// reconcileDeletion honors Bucket.Spec.ReclaimPolicy: Retain releases the
+// Bucket without touching the backing storage bucket, Delete (the default)
+// calls the provisioner and only removes bucketProtectionFinalizer once that
+// succeeds, so a failure leaves the finalizer in place for the workqueue to
+// retry. It is idempotent: a Bucket that has already lost the finalizer is
+// treated as already reconciled.
+func (bl *bucketListener) reconcileDeletion(ctx context.Context, obj *v1alpha1.Bucket) error {
 	// Verify this bucket is for this provisioner
 	if !strings.EqualFold(obj.Spec.Provisioner, bl.provisionerName) {
 		return nil
 	}
 
+	if !hasFinalizer(obj, bucketProtectionFinalizer) {
+		klog.V(1).Infof("bucket %s has no %s finalizer, nothing to do", obj.Name, bucketProtectionFinalizer)
+		return nil
+	}
+
+	if obj.Spec.ReclaimPolicy == v1alpha1.ReclaimPolicyRetain {
+		klog.V(1).Infof("bucket %s has a Retain reclaim policy, releasing without deleting the backing bucket", obj.Name)
+		apimeta.SetStatusCondition(&obj.Status.Conditions, metav1.Condition{
+			Type:               bucketConditionReleased,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: obj.Generation,
+			Reason:             "ReclaimPolicyRetain",
+			Message:            "bucket released without deleting the backing storage bucket",
+		})
+		// BucketReleased lives on the status subresource, so it needs its own
+		// UpdateStatus call: the plain Update below only carries the
+		// finalizer removal, and on a cluster with the status subresource
+		// enabled it would silently drop this condition.
+		if err := bl.persistStatus(ctx, obj); err != nil {
+			return err
+		}
+	} else {
+		if err := bl.deleteProvisionerBucket(ctx, obj); err != nil {
+			return err
+		}
+	}
+
+	removeFinalizer(obj, bucketProtectionFinalizer)
+	_, err := bl.bucketClient.ObjectstorageV1alpha1().Buckets().Update(ctx, obj, metav1.UpdateOptions{})
+	if err != nil {
+		klog.Errorf("error removing finalizer from bucket %s: %v", obj.Name, err)
+		return err
+	}
+
+	if err := bl.ensureIndexer().Delete(obj); err != nil {
+		klog.Errorf("error evicting bucket %s from cache: %v", obj.Name, err)
+	}
+	bl.forgetApplied(obj.Name)
+	return nil
+}
+
+// deleteProvisionerBucket calls the provisioner to delete the backing
+// storage bucket, clearing tags first on a best-effort basis.
+func (bl *bucketListener) deleteProvisionerBucket(ctx context.Context, obj *v1alpha1.Bucket) error {
 	req := osspec.ProvisionerDeleteBucketRequest{
 		BucketName: obj.Spec.BucketRequest.Name,
 	}
@@ -157,6 +776,16 @@ func (bl *bucketListener) Delete(ctx context.Context, obj *v1alpha1.Bucket) erro
 		return fmt.Errorf(errStr)
 	}
 
+	// Clear tags ahead of bucket removal. This is best-effort: a failure here
+	// shouldn't block deletion of the bucket itself.
+	if len(obj.Spec.Tags) > 0 {
+		if _, err := bl.provisionerClient.ProvisionerDeleteBucketTagging(ctx, &osspec.ProvisionerDeleteBucketTaggingRequest{
+			BucketName: obj.Spec.BucketRequest.Name,
+		}); err != nil {
+			klog.Warningf("unable to clear tags on bucket %s before deletion: %v", obj.Name, err)
+		}
+	}
+
 	// TODO set grpc timeout
 	rsp, err := bl.provisionerClient.ProvisionerDeleteBucket(ctx, &req)
 	if err != nil {
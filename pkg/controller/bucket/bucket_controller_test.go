@@ -18,7 +18,9 @@ package bucket
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/container-object-storage-interface/api/apis/objectstorage.k8s.io/v1alpha1"
@@ -28,15 +30,32 @@ import (
 	osspec "github.com/container-object-storage-interface/spec"
 	fakespec "github.com/container-object-storage-interface/spec/fake"
 
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilversion "k8s.io/apimachinery/pkg/util/version"
 	"k8s.io/apimachinery/pkg/version"
 
 	fakediscovery "k8s.io/client-go/discovery/fake"
 	fakekubeclientset "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
 
 	"google.golang.org/grpc"
 )
 
+// newBucketIndexer builds a standalone indexer, preloaded with buckets,
+// equivalent to the one InitializeBucketClient wires up, without having to
+// go through Add/Update/Delete to populate it.
+func newBucketIndexer(t *testing.T, buckets ...*v1alpha1.Bucket) cache.Indexer {
+	t.Helper()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{byProvisionerIndex: indexBucketByProvisioner})
+	for _, b := range buckets {
+		if err := indexer.Add(b); err != nil {
+			t.Fatalf("failed to seed indexer: %v", err)
+		}
+	}
+	return indexer
+}
+
 func TestInitializeKubeClient(t *testing.T) {
 	client := fakekubeclientset.NewSimpleClientset()
 	fakeDiscovery, ok := client.Discovery().(*fakediscovery.FakeDiscovery)
@@ -189,6 +208,45 @@ func TestAddValidProtocols(t *testing.T) {
 		if b.Status.BucketAvailable != true {
 			t.Errorf("expected %t, got %t", true, b.Status.BucketAvailable)
 		}
+		if !hasFinalizer(&b, bucketProtectionFinalizer) {
+			t.Errorf("expected %s finalizer to be added", bucketProtectionFinalizer)
+		}
+	}
+}
+
+func TestAddIsIdempotentAboutFinalizer(t *testing.T) {
+	provisioner := "provisioner1"
+	bucketName := "bucket1"
+	mpc := struct{ fakespec.MockProvisionerClient }{}
+	mpc.CreateBucket = func(ctx context.Context, in *osspec.ProvisionerCreateBucketRequest, opts ...grpc.CallOption) (*osspec.ProvisionerCreateBucketResponse, error) {
+		return &osspec.ProvisionerCreateBucketResponse{}, nil
+	}
+
+	b := v1alpha1.Bucket{
+		Spec: v1alpha1.BucketSpec{
+			BucketRequest: &v1alpha1.ObjectReference{Name: bucketName},
+			Provisioner:   provisioner,
+			Protocol: v1alpha1.Protocol{
+				RequestedProtocol: v1alpha1.RequestedProtocol{Name: v1alpha1.ProtocolNameS3},
+			},
+		},
+	}
+	b.Spec.Protocol.S3 = &v1alpha1.S3Protocol{}
+	b.Finalizers = []string{bucketProtectionFinalizer}
+
+	ctx := context.TODO()
+	client := fakebucketclientset.NewSimpleClientset(&b)
+	bl := bucketListener{
+		provisionerName:   provisioner,
+		provisionerClient: &mpc,
+		bucketClient:      client,
+	}
+
+	if err := bl.Add(ctx, &b); err != nil {
+		t.Errorf("add returned: %+v", err)
+	}
+	if len(b.Finalizers) != 1 {
+		t.Errorf("expected finalizer to be added once, got %v", b.Finalizers)
 	}
 }
 
@@ -322,6 +380,7 @@ func TestDeleteValidProtocols(t *testing.T) {
 				},
 			},
 		}
+		b.Finalizers = []string{bucketProtectionFinalizer}
 
 		ctx := context.TODO()
 		client := fakebucketclientset.NewSimpleClientset(&b)
@@ -338,6 +397,1047 @@ func TestDeleteValidProtocols(t *testing.T) {
 		if err != nil {
 			t.Errorf("delete returned: %+v", err)
 		}
+		if hasFinalizer(&b, bucketProtectionFinalizer) {
+			t.Errorf("expected finalizer to be removed after successful delete")
+		}
+	}
+}
+
+func TestValidateTags(t *testing.T) {
+	testCases := []struct {
+		name    string
+		tags    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "nil tags",
+			tags: nil,
+		},
+		{
+			name: "valid tags",
+			tags: map[string]string{"env": "prod", "team": "storage"},
+		},
+		{
+			name: "too many tags",
+			tags: func() map[string]string {
+				tags := map[string]string{}
+				for i := 0; i < maxBucketTags+1; i++ {
+					tags[fmt.Sprintf("key%d", i)] = "value"
+				}
+				return tags
+			}(),
+			wantErr: true,
+		},
+		{
+			name:    "key too long",
+			tags:    map[string]string{strings.Repeat("k", maxTagKeyLength+1): "value"},
+			wantErr: true,
+		},
+		{
+			name:    "value too long",
+			tags:    map[string]string{"key": strings.Repeat("v", maxTagValueLength+1)},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateTags(tc.tags)
+			if tc.wantErr && err == nil {
+				t.Errorf("expected error, got nil")
+			}
+			if !tc.wantErr && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+// tagProtocolTestCases covers the protocol-specific setup the tag-handling
+// tests run against each of S3, GCS and AzureBlob: tags are provisioner-
+// agnostic, so these tests only need each protocol's Spec.Protocol wired up
+// correctly, not its own RPC behavior (that's TestAddValidProtocols/
+// TestDeleteValidProtocols).
+func tagProtocolTestCases() []struct {
+	name         string
+	protocolName v1alpha1.ProtocolName
+	setProtocol  func(b *v1alpha1.Bucket)
+} {
+	return []struct {
+		name         string
+		protocolName v1alpha1.ProtocolName
+		setProtocol  func(b *v1alpha1.Bucket)
+	}{
+		{
+			name:         "S3",
+			protocolName: v1alpha1.ProtocolNameS3,
+			setProtocol: func(b *v1alpha1.Bucket) {
+				b.Spec.Protocol.S3 = &v1alpha1.S3Protocol{}
+			},
+		},
+		{
+			name:         "GCS",
+			protocolName: v1alpha1.ProtocolNameGCS,
+			setProtocol: func(b *v1alpha1.Bucket) {
+				b.Spec.Protocol.GCS = &v1alpha1.GCSProtocol{}
+			},
+		},
+		{
+			name:         "AzureBlob",
+			protocolName: v1alpha1.ProtocolNameAzure,
+			setProtocol: func(b *v1alpha1.Bucket) {
+				b.Spec.Protocol.AzureBlob = &v1alpha1.AzureProtocol{}
+			},
+		},
+	}
+}
+
+func TestAddSendsTags(t *testing.T) {
+	provisioner := "provisioner1"
+	bucketName := "bucket1"
+	tags := map[string]string{"env": "prod"}
+
+	for _, tc := range tagProtocolTestCases() {
+		mpc := struct{ fakespec.MockProvisionerClient }{}
+		mpc.CreateBucket = func(ctx context.Context, in *osspec.ProvisionerCreateBucketRequest, opts ...grpc.CallOption) (*osspec.ProvisionerCreateBucketResponse, error) {
+			if !reflect.DeepEqual(in.Tags, tags) {
+				t.Errorf("expected tags %+v, got %+v", tags, in.Tags)
+			}
+			if in.TaggingDirective != osspec.TaggingDirectiveReplace {
+				t.Errorf("expected directive %v, got %v", osspec.TaggingDirectiveReplace, in.TaggingDirective)
+			}
+			return &osspec.ProvisionerCreateBucketResponse{}, nil
+		}
+
+		b := v1alpha1.Bucket{
+			Spec: v1alpha1.BucketSpec{
+				BucketRequest: &v1alpha1.ObjectReference{
+					Name: bucketName,
+				},
+				Provisioner: provisioner,
+				Tags:        tags,
+				Protocol: v1alpha1.Protocol{
+					RequestedProtocol: v1alpha1.RequestedProtocol{
+						Name: tc.protocolName,
+					},
+				},
+			},
+		}
+		tc.setProtocol(&b)
+
+		ctx := context.TODO()
+		client := fakebucketclientset.NewSimpleClientset(&b)
+		bl := bucketListener{
+			provisionerName:   provisioner,
+			provisionerClient: &mpc,
+			bucketClient:      client,
+		}
+
+		t.Logf("Testing protocol %s", tc.name)
+		if err := bl.Add(ctx, &b); err != nil {
+			t.Errorf("add returned: %+v", err)
+		}
+	}
+}
+
+func TestAddSendsCopyTaggingDirective(t *testing.T) {
+	provisioner := "provisioner1"
+	bucketName := "bucket1"
+	tags := map[string]string{"env": "prod"}
+
+	for _, tc := range tagProtocolTestCases() {
+		mpc := struct{ fakespec.MockProvisionerClient }{}
+		mpc.CreateBucket = func(ctx context.Context, in *osspec.ProvisionerCreateBucketRequest, opts ...grpc.CallOption) (*osspec.ProvisionerCreateBucketResponse, error) {
+			if in.TaggingDirective != osspec.TaggingDirectiveCopy {
+				t.Errorf("expected directive %v, got %v", osspec.TaggingDirectiveCopy, in.TaggingDirective)
+			}
+			return &osspec.ProvisionerCreateBucketResponse{}, nil
+		}
+
+		b := v1alpha1.Bucket{
+			Spec: v1alpha1.BucketSpec{
+				BucketRequest: &v1alpha1.ObjectReference{
+					Name: bucketName,
+				},
+				Provisioner:      provisioner,
+				Tags:             tags,
+				TaggingDirective: v1alpha1.TaggingDirectiveCopy,
+				Protocol: v1alpha1.Protocol{
+					RequestedProtocol: v1alpha1.RequestedProtocol{
+						Name: tc.protocolName,
+					},
+				},
+			},
+		}
+		tc.setProtocol(&b)
+
+		ctx := context.TODO()
+		client := fakebucketclientset.NewSimpleClientset(&b)
+		bl := bucketListener{
+			provisionerName:   provisioner,
+			provisionerClient: &mpc,
+			bucketClient:      client,
+		}
+
+		t.Logf("Testing protocol %s", tc.name)
+		if err := bl.Add(ctx, &b); err != nil {
+			t.Errorf("add returned: %+v", err)
+		}
+	}
+}
+
+func TestAddRejectsInvalidTags(t *testing.T) {
+	provisioner := "provisioner1"
+
+	for _, tc := range tagProtocolTestCases() {
+		bl := bucketListener{
+			provisionerName: provisioner,
+		}
+
+		b := v1alpha1.Bucket{
+			Spec: v1alpha1.BucketSpec{
+				BucketRequest: &v1alpha1.ObjectReference{
+					Name: "bucket1",
+				},
+				Provisioner: provisioner,
+				Tags:        map[string]string{"": "value"},
+				Protocol: v1alpha1.Protocol{
+					RequestedProtocol: v1alpha1.RequestedProtocol{
+						Name: tc.protocolName,
+					},
+				},
+			},
+		}
+		tc.setProtocol(&b)
+
+		t.Logf("Testing protocol %s", tc.name)
+		ctx := context.TODO()
+		if err := bl.Add(ctx, &b); err == nil {
+			t.Errorf("expected error for invalid tags, got nil")
+		}
+	}
+}
+
+func TestUpdateTagsUnchangedDoesNotCallProvisioner(t *testing.T) {
+	provisioner := "provisioner1"
+	tags := map[string]string{"env": "prod"}
+	mpc := struct{ fakespec.MockProvisionerClient }{}
+	mpc.SetBucketTagging = func(ctx context.Context, in *osspec.ProvisionerSetBucketTaggingRequest, opts ...grpc.CallOption) (*osspec.ProvisionerSetBucketTaggingResponse, error) {
+		t.Errorf("grpc client called")
+		return nil, nil
+	}
+	mpc.DeleteBucketTagging = func(ctx context.Context, in *osspec.ProvisionerDeleteBucketTaggingRequest, opts ...grpc.CallOption) (*osspec.ProvisionerDeleteBucketTaggingResponse, error) {
+		t.Errorf("grpc client called")
+		return nil, nil
+	}
+
+	bl := bucketListener{
+		provisionerName:   provisioner,
+		provisionerClient: &mpc,
+	}
+
+	old := v1alpha1.Bucket{
+		Spec: v1alpha1.BucketSpec{
+			Provisioner: provisioner,
+			Tags:        tags,
+		},
+	}
+	new := old.DeepCopy()
+
+	ctx := context.TODO()
+	if err := bl.Update(ctx, &old, new); err != nil {
+		t.Errorf("update returned: %+v", err)
+	}
+}
+
+func TestUpdateTagsReplaced(t *testing.T) {
+	provisioner := "provisioner1"
+	bucketName := "bucket1"
+	oldTags := map[string]string{"env": "dev"}
+	newTags := map[string]string{"env": "prod", "team": "storage"}
+
+	for _, tc := range tagProtocolTestCases() {
+		mpc := struct{ fakespec.MockProvisionerClient }{}
+		mpc.SetBucketTagging = func(ctx context.Context, in *osspec.ProvisionerSetBucketTaggingRequest, opts ...grpc.CallOption) (*osspec.ProvisionerSetBucketTaggingResponse, error) {
+			if in.BucketName != bucketName {
+				t.Errorf("expected %s, got %s", bucketName, in.BucketName)
+			}
+			if !reflect.DeepEqual(in.Tags, newTags) {
+				t.Errorf("expected tags %+v, got %+v", newTags, in.Tags)
+			}
+			if in.TaggingDirective != osspec.TaggingDirectiveReplace {
+				t.Errorf("expected directive %v, got %v", osspec.TaggingDirectiveReplace, in.TaggingDirective)
+			}
+			return &osspec.ProvisionerSetBucketTaggingResponse{}, nil
+		}
+
+		old := v1alpha1.Bucket{
+			Spec: v1alpha1.BucketSpec{
+				BucketRequest: &v1alpha1.ObjectReference{Name: bucketName},
+				Provisioner:   provisioner,
+				Tags:          oldTags,
+				Protocol: v1alpha1.Protocol{
+					RequestedProtocol: v1alpha1.RequestedProtocol{Name: tc.protocolName},
+				},
+			},
+		}
+		tc.setProtocol(&old)
+		new := old.DeepCopy()
+		new.Spec.Tags = newTags
+
+		client := fakebucketclientset.NewSimpleClientset(new)
+		bl := bucketListener{
+			provisionerName:   provisioner,
+			provisionerClient: &mpc,
+			bucketClient:      client,
+		}
+
+		t.Logf("Testing protocol %s", tc.name)
+		ctx := context.TODO()
+		if err := bl.Update(ctx, &old, new); err != nil {
+			t.Errorf("update returned: %+v", err)
+		}
+		cond := apimeta.FindStatusCondition(new.Status.Conditions, bucketConditionUpdated)
+		if cond == nil || cond.Status != metav1.ConditionTrue {
+			t.Errorf("expected %s condition to be True, got %+v", bucketConditionUpdated, cond)
+		}
+	}
+}
+
+func TestUpdateTagsCopyDirective(t *testing.T) {
+	provisioner := "provisioner1"
+	bucketName := "bucket1"
+	oldTags := map[string]string{"env": "dev"}
+	newTags := map[string]string{"env": "prod", "team": "storage"}
+
+	for _, tc := range tagProtocolTestCases() {
+		mpc := struct{ fakespec.MockProvisionerClient }{}
+		mpc.SetBucketTagging = func(ctx context.Context, in *osspec.ProvisionerSetBucketTaggingRequest, opts ...grpc.CallOption) (*osspec.ProvisionerSetBucketTaggingResponse, error) {
+			if in.TaggingDirective != osspec.TaggingDirectiveCopy {
+				t.Errorf("expected directive %v, got %v", osspec.TaggingDirectiveCopy, in.TaggingDirective)
+			}
+			return &osspec.ProvisionerSetBucketTaggingResponse{}, nil
+		}
+
+		old := v1alpha1.Bucket{
+			Spec: v1alpha1.BucketSpec{
+				BucketRequest:    &v1alpha1.ObjectReference{Name: bucketName},
+				Provisioner:      provisioner,
+				Tags:             oldTags,
+				TaggingDirective: v1alpha1.TaggingDirectiveCopy,
+				Protocol: v1alpha1.Protocol{
+					RequestedProtocol: v1alpha1.RequestedProtocol{Name: tc.protocolName},
+				},
+			},
+		}
+		tc.setProtocol(&old)
+		new := old.DeepCopy()
+		new.Spec.Tags = newTags
+
+		client := fakebucketclientset.NewSimpleClientset(new)
+		bl := bucketListener{
+			provisionerName:   provisioner,
+			provisionerClient: &mpc,
+			bucketClient:      client,
+		}
+
+		t.Logf("Testing protocol %s", tc.name)
+		ctx := context.TODO()
+		if err := bl.Update(ctx, &old, new); err != nil {
+			t.Errorf("update returned: %+v", err)
+		}
+	}
+}
+
+func TestUpdateTagsRemoved(t *testing.T) {
+	provisioner := "provisioner1"
+	bucketName := "bucket1"
+
+	for _, tc := range tagProtocolTestCases() {
+		mpc := struct{ fakespec.MockProvisionerClient }{}
+		mpc.DeleteBucketTagging = func(ctx context.Context, in *osspec.ProvisionerDeleteBucketTaggingRequest, opts ...grpc.CallOption) (*osspec.ProvisionerDeleteBucketTaggingResponse, error) {
+			if in.BucketName != bucketName {
+				t.Errorf("expected %s, got %s", bucketName, in.BucketName)
+			}
+			return &osspec.ProvisionerDeleteBucketTaggingResponse{}, nil
+		}
+
+		old := v1alpha1.Bucket{
+			Spec: v1alpha1.BucketSpec{
+				BucketRequest: &v1alpha1.ObjectReference{Name: bucketName},
+				Provisioner:   provisioner,
+				Tags:          map[string]string{"env": "dev"},
+				Protocol: v1alpha1.Protocol{
+					RequestedProtocol: v1alpha1.RequestedProtocol{Name: tc.protocolName},
+				},
+			},
+		}
+		tc.setProtocol(&old)
+		new := old.DeepCopy()
+		new.Spec.Tags = nil
+
+		client := fakebucketclientset.NewSimpleClientset(new)
+		bl := bucketListener{
+			provisionerName:   provisioner,
+			provisionerClient: &mpc,
+			bucketClient:      client,
+		}
+
+		t.Logf("Testing protocol %s", tc.name)
+		ctx := context.TODO()
+		if err := bl.Update(ctx, &old, new); err != nil {
+			t.Errorf("update returned: %+v", err)
+		}
+	}
+}
+
+func TestDeleteClearsTagsBeforeRemoval(t *testing.T) {
+	provisioner := "provisioner1"
+	bucketName := "bucket1"
+
+	for _, tc := range tagProtocolTestCases() {
+		mpc := struct{ fakespec.MockProvisionerClient }{}
+		cleared := false
+		mpc.DeleteBucketTagging = func(ctx context.Context, in *osspec.ProvisionerDeleteBucketTaggingRequest, opts ...grpc.CallOption) (*osspec.ProvisionerDeleteBucketTaggingResponse, error) {
+			cleared = true
+			return &osspec.ProvisionerDeleteBucketTaggingResponse{}, nil
+		}
+		mpc.DeleteBucket = func(ctx context.Context, in *osspec.ProvisionerDeleteBucketRequest, opts ...grpc.CallOption) (*osspec.ProvisionerDeleteBucketResponse, error) {
+			if !cleared {
+				t.Errorf("expected tags to be cleared before bucket deletion")
+			}
+			return &osspec.ProvisionerDeleteBucketResponse{}, nil
+		}
+
+		b := v1alpha1.Bucket{
+			Spec: v1alpha1.BucketSpec{
+				BucketRequest: &v1alpha1.ObjectReference{Name: bucketName},
+				Provisioner:   provisioner,
+				Tags:          map[string]string{"env": "dev"},
+				Protocol: v1alpha1.Protocol{
+					RequestedProtocol: v1alpha1.RequestedProtocol{Name: tc.protocolName},
+				},
+			},
+		}
+		tc.setProtocol(&b)
+		b.Finalizers = []string{bucketProtectionFinalizer}
+
+		client := fakebucketclientset.NewSimpleClientset(&b)
+		bl := bucketListener{
+			provisionerName:   provisioner,
+			provisionerClient: &mpc,
+			bucketClient:      client,
+		}
+
+		t.Logf("Testing protocol %s", tc.name)
+		ctx := context.TODO()
+		if err := bl.Delete(ctx, &b); err != nil {
+			t.Errorf("delete returned: %+v", err)
+		}
+		if !cleared {
+			t.Errorf("expected ProvisionerDeleteBucketTagging to be called")
+		}
+	}
+}
+
+func TestUpdateNoopSpecDoesNothing(t *testing.T) {
+	provisioner := "provisioner1"
+	mpc := struct{ fakespec.MockProvisionerClient }{}
+	mpc.UpdateBucket = func(ctx context.Context, in *osspec.ProvisionerUpdateBucketRequest, opts ...grpc.CallOption) (*osspec.ProvisionerUpdateBucketResponse, error) {
+		t.Errorf("grpc client called")
+		return nil, nil
+	}
+
+	bl := bucketListener{
+		provisionerName:   provisioner,
+		provisionerClient: &mpc,
+	}
+
+	old := v1alpha1.Bucket{
+		Spec: v1alpha1.BucketSpec{
+			BucketRequest: &v1alpha1.ObjectReference{Name: "bucket1"},
+			Provisioner:   provisioner,
+			Protocol: v1alpha1.Protocol{
+				RequestedProtocol: v1alpha1.RequestedProtocol{Name: v1alpha1.ProtocolNameS3},
+			},
+		},
+	}
+	old.Spec.Protocol.S3 = &v1alpha1.S3Protocol{Region: "region1"}
+	new := old.DeepCopy()
+
+	ctx := context.TODO()
+	if err := bl.Update(ctx, &old, new); err != nil {
+		t.Errorf("update returned: %+v", err)
+	}
+}
+
+func TestUpdateRejectsImmutableFields(t *testing.T) {
+	provisioner := "provisioner1"
+
+	testCases := []struct {
+		name   string
+		mutate func(b *v1alpha1.Bucket)
+	}{
+		{
+			name: "provisioner changed",
+			mutate: func(b *v1alpha1.Bucket) {
+				b.Spec.Provisioner = "provisioner2"
+			},
+		},
+		{
+			name: "protocol changed",
+			mutate: func(b *v1alpha1.Bucket) {
+				b.Spec.Protocol.Name = v1alpha1.ProtocolNameGCS
+			},
+		},
+		{
+			name: "bucket name changed",
+			mutate: func(b *v1alpha1.Bucket) {
+				b.Spec.BucketRequest.Name = "renamed"
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mpc := struct{ fakespec.MockProvisionerClient }{}
+			mpc.UpdateBucket = func(ctx context.Context, in *osspec.ProvisionerUpdateBucketRequest, opts ...grpc.CallOption) (*osspec.ProvisionerUpdateBucketResponse, error) {
+				t.Errorf("grpc client called")
+				return nil, nil
+			}
+
+			old := v1alpha1.Bucket{
+				Spec: v1alpha1.BucketSpec{
+					BucketRequest: &v1alpha1.ObjectReference{Name: "bucket1"},
+					Provisioner:   provisioner,
+					Protocol: v1alpha1.Protocol{
+						RequestedProtocol: v1alpha1.RequestedProtocol{Name: v1alpha1.ProtocolNameS3},
+					},
+				},
+			}
+			old.Spec.Protocol.S3 = &v1alpha1.S3Protocol{Region: "region1"}
+
+			client := fakebucketclientset.NewSimpleClientset(&old)
+			bl := bucketListener{
+				provisionerName:   provisioner,
+				provisionerClient: &mpc,
+				bucketClient:      client,
+			}
+
+			new := old.DeepCopy()
+			tc.mutate(new)
+
+			if err := bl.Update(context.TODO(), &old, new); err != nil {
+				t.Errorf("update returned: %+v", err)
+			}
+
+			cond := apimeta.FindStatusCondition(new.Status.Conditions, bucketConditionUpdateFailed)
+			if cond == nil || cond.Status != metav1.ConditionTrue {
+				t.Errorf("expected %s condition to be True, got %+v", bucketConditionUpdateFailed, cond)
+			}
+			if cond.Reason != reasonImmutableField {
+				t.Errorf("expected reason %s, got %s", reasonImmutableField, cond.Reason)
+			}
+		})
+	}
+}
+
+func TestUpdateMutableFieldPerProtocol(t *testing.T) {
+	provisioner := "provisioner1"
+	bucketName := "bucket1"
+
+	testCases := []struct {
+		name        string
+		setProtocol func(b *v1alpha1.Bucket, region string)
+		protocol    v1alpha1.ProtocolName
+	}{
+		{
+			name: "S3",
+			setProtocol: func(b *v1alpha1.Bucket, region string) {
+				b.Spec.Protocol.S3 = &v1alpha1.S3Protocol{Region: region}
+			},
+			protocol: v1alpha1.ProtocolNameS3,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			called := false
+			mpc := struct{ fakespec.MockProvisionerClient }{}
+			mpc.UpdateBucket = func(ctx context.Context, in *osspec.ProvisionerUpdateBucketRequest, opts ...grpc.CallOption) (*osspec.ProvisionerUpdateBucketResponse, error) {
+				called = true
+				if in.BucketName != bucketName {
+					t.Errorf("expected %s, got %s", bucketName, in.BucketName)
+				}
+				return &osspec.ProvisionerUpdateBucketResponse{}, nil
+			}
+
+			old := v1alpha1.Bucket{
+				Spec: v1alpha1.BucketSpec{
+					BucketRequest: &v1alpha1.ObjectReference{Name: bucketName},
+					Provisioner:   provisioner,
+					Protocol: v1alpha1.Protocol{
+						RequestedProtocol: v1alpha1.RequestedProtocol{Name: tc.protocol},
+					},
+				},
+			}
+			tc.setProtocol(&old, "region1")
+
+			client := fakebucketclientset.NewSimpleClientset(&old)
+			bl := bucketListener{
+				provisionerName:   provisioner,
+				provisionerClient: &mpc,
+				bucketClient:      client,
+			}
+
+			new := old.DeepCopy()
+			tc.setProtocol(new, "region2")
+
+			if err := bl.Update(context.TODO(), &old, new); err != nil {
+				t.Errorf("update returned: %+v", err)
+			}
+			if !called {
+				t.Errorf("expected ProvisionerUpdateBucket to be called")
+			}
+
+			cond := apimeta.FindStatusCondition(new.Status.Conditions, bucketConditionUpdated)
+			if cond == nil || cond.Status != metav1.ConditionTrue {
+				t.Errorf("expected %s condition to be True, got %+v", bucketConditionUpdated, cond)
+			}
+		})
+	}
+}
+
+func TestUpdatePropagatesProvisionerError(t *testing.T) {
+	provisioner := "provisioner1"
+	bucketName := "bucket1"
+	mpc := struct{ fakespec.MockProvisionerClient }{}
+	mpc.UpdateBucket = func(ctx context.Context, in *osspec.ProvisionerUpdateBucketRequest, opts ...grpc.CallOption) (*osspec.ProvisionerUpdateBucketResponse, error) {
+		return nil, fmt.Errorf("provisioner unavailable")
+	}
+
+	old := v1alpha1.Bucket{
+		Spec: v1alpha1.BucketSpec{
+			BucketRequest: &v1alpha1.ObjectReference{Name: bucketName},
+			Provisioner:   provisioner,
+			Protocol: v1alpha1.Protocol{
+				RequestedProtocol: v1alpha1.RequestedProtocol{Name: v1alpha1.ProtocolNameS3},
+			},
+		},
+	}
+	old.Spec.Protocol.S3 = &v1alpha1.S3Protocol{Region: "region1"}
+
+	client := fakebucketclientset.NewSimpleClientset(&old)
+	bl := bucketListener{
+		provisionerName:   provisioner,
+		provisionerClient: &mpc,
+		bucketClient:      client,
+	}
+
+	new := old.DeepCopy()
+	new.Spec.Protocol.S3 = &v1alpha1.S3Protocol{Region: "region2"}
+
+	err := bl.Update(context.TODO(), &old, new)
+	if err == nil {
+		t.Errorf("expected error to be returned")
+	}
+
+	cond := apimeta.FindStatusCondition(new.Status.Conditions, bucketConditionUpdateFailed)
+	if cond == nil || cond.Status != metav1.ConditionTrue {
+		t.Errorf("expected %s condition to be True, got %+v", bucketConditionUpdateFailed, cond)
+	}
+	if cond.Reason != reasonProvisionerErr {
+		t.Errorf("expected reason %s, got %s", reasonProvisionerErr, cond.Reason)
+	}
+}
+
+func TestReconcileDeletionRetainReleasesWithoutCallingProvisioner(t *testing.T) {
+	provisioner := "provisioner1"
+	bucketName := "bucket1"
+	mpc := struct{ fakespec.MockProvisionerClient }{}
+	mpc.DeleteBucket = func(ctx context.Context, in *osspec.ProvisionerDeleteBucketRequest, opts ...grpc.CallOption) (*osspec.ProvisionerDeleteBucketResponse, error) {
+		t.Errorf("grpc client called")
+		return nil, nil
+	}
+
+	b := v1alpha1.Bucket{
+		ObjectMeta: metav1.ObjectMeta{Name: bucketName},
+		Spec: v1alpha1.BucketSpec{
+			BucketRequest: &v1alpha1.ObjectReference{Name: bucketName},
+			Provisioner:   provisioner,
+			ReclaimPolicy: v1alpha1.ReclaimPolicyRetain,
+			Protocol: v1alpha1.Protocol{
+				RequestedProtocol: v1alpha1.RequestedProtocol{Name: v1alpha1.ProtocolNameS3},
+			},
+		},
+	}
+	b.Spec.Protocol.S3 = &v1alpha1.S3Protocol{}
+	b.Finalizers = []string{bucketProtectionFinalizer}
+
+	client := fakebucketclientset.NewSimpleClientset(&b)
+	bl := bucketListener{
+		provisionerName:   provisioner,
+		provisionerClient: &mpc,
+		bucketClient:      client,
+	}
+
+	ctx := context.TODO()
+	if err := bl.reconcileDeletion(ctx, &b); err != nil {
+		t.Errorf("reconcileDeletion returned: %+v", err)
+	}
+	if hasFinalizer(&b, bucketProtectionFinalizer) {
+		t.Errorf("expected finalizer to be removed")
+	}
+	cond := apimeta.FindStatusCondition(b.Status.Conditions, bucketConditionReleased)
+	if cond == nil || cond.Status != metav1.ConditionTrue {
+		t.Errorf("expected %s condition to be True, got %+v", bucketConditionReleased, cond)
+	}
+
+	// The in-memory obj isn't proof the write stuck, so re-fetch from the
+	// fake client to confirm the condition was persisted. The fake client
+	// stores the whole object on both Update and UpdateStatus, so this check
+	// does not tell which of the two calls carried the condition.
+	stored, err := client.ObjectstorageV1alpha1().Buckets().Get(ctx, bucketName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unable to re-fetch bucket: %v", err)
+	}
+	storedCond := apimeta.FindStatusCondition(stored.Status.Conditions, bucketConditionReleased)
+	if storedCond == nil || storedCond.Status != metav1.ConditionTrue {
+		t.Errorf("expected persisted %s condition to be True, got %+v", bucketConditionReleased, storedCond)
+	}
+}
+
+func TestReconcileDeletionDeletePolicyCallsProvisioner(t *testing.T) {
+	provisioner := "provisioner1"
+	bucketName := "bucket1"
+	mpc := struct{ fakespec.MockProvisionerClient }{}
+	called := false
+	mpc.DeleteBucket = func(ctx context.Context, in *osspec.ProvisionerDeleteBucketRequest, opts ...grpc.CallOption) (*osspec.ProvisionerDeleteBucketResponse, error) {
+		called = true
+		return &osspec.ProvisionerDeleteBucketResponse{}, nil
+	}
+
+	b := v1alpha1.Bucket{
+		Spec: v1alpha1.BucketSpec{
+			BucketRequest: &v1alpha1.ObjectReference{Name: bucketName},
+			Provisioner:   provisioner,
+			ReclaimPolicy: v1alpha1.ReclaimPolicyDelete,
+			Protocol: v1alpha1.Protocol{
+				RequestedProtocol: v1alpha1.RequestedProtocol{Name: v1alpha1.ProtocolNameS3},
+			},
+		},
+	}
+	b.Spec.Protocol.S3 = &v1alpha1.S3Protocol{}
+	b.Finalizers = []string{bucketProtectionFinalizer}
+
+	client := fakebucketclientset.NewSimpleClientset(&b)
+	bl := bucketListener{
+		provisionerName:   provisioner,
+		provisionerClient: &mpc,
+		bucketClient:      client,
+	}
+
+	ctx := context.TODO()
+	if err := bl.reconcileDeletion(ctx, &b); err != nil {
+		t.Errorf("reconcileDeletion returned: %+v", err)
+	}
+	if !called {
+		t.Errorf("expected ProvisionerDeleteBucket to be called")
+	}
+	if hasFinalizer(&b, bucketProtectionFinalizer) {
+		t.Errorf("expected finalizer to be removed")
+	}
+}
+
+func TestReconcileDeletionRetriesOnProvisionerError(t *testing.T) {
+	provisioner := "provisioner1"
+	bucketName := "bucket1"
+	mpc := struct{ fakespec.MockProvisionerClient }{}
+	mpc.DeleteBucket = func(ctx context.Context, in *osspec.ProvisionerDeleteBucketRequest, opts ...grpc.CallOption) (*osspec.ProvisionerDeleteBucketResponse, error) {
+		return nil, fmt.Errorf("provisioner unavailable")
+	}
+
+	b := v1alpha1.Bucket{
+		Spec: v1alpha1.BucketSpec{
+			BucketRequest: &v1alpha1.ObjectReference{Name: bucketName},
+			Provisioner:   provisioner,
+			Protocol: v1alpha1.Protocol{
+				RequestedProtocol: v1alpha1.RequestedProtocol{Name: v1alpha1.ProtocolNameS3},
+			},
+		},
+	}
+	b.Spec.Protocol.S3 = &v1alpha1.S3Protocol{}
+	b.Finalizers = []string{bucketProtectionFinalizer}
+
+	client := fakebucketclientset.NewSimpleClientset(&b)
+	bl := bucketListener{
+		provisionerName:   provisioner,
+		provisionerClient: &mpc,
+		bucketClient:      client,
+	}
+
+	ctx := context.TODO()
+	if err := bl.reconcileDeletion(ctx, &b); err == nil {
+		t.Errorf("expected error to be returned so the workqueue retries")
+	}
+	if !hasFinalizer(&b, bucketProtectionFinalizer) {
+		t.Errorf("expected finalizer to remain until deletion succeeds")
+	}
+}
+
+func TestReconcileDeletionIdempotentWithoutFinalizer(t *testing.T) {
+	provisioner := "provisioner1"
+	mpc := struct{ fakespec.MockProvisionerClient }{}
+	mpc.DeleteBucket = func(ctx context.Context, in *osspec.ProvisionerDeleteBucketRequest, opts ...grpc.CallOption) (*osspec.ProvisionerDeleteBucketResponse, error) {
+		t.Errorf("grpc client called")
+		return nil, nil
+	}
+
+	b := v1alpha1.Bucket{
+		Spec: v1alpha1.BucketSpec{
+			BucketRequest: &v1alpha1.ObjectReference{Name: "bucket1"},
+			Provisioner:   provisioner,
+			Protocol: v1alpha1.Protocol{
+				RequestedProtocol: v1alpha1.RequestedProtocol{Name: v1alpha1.ProtocolNameS3},
+			},
+		},
+	}
+	b.Spec.Protocol.S3 = &v1alpha1.S3Protocol{}
+
+	bl := bucketListener{
+		provisionerName:   provisioner,
+		provisionerClient: &mpc,
+	}
+
+	ctx := context.TODO()
+	if err := bl.reconcileDeletion(ctx, &b); err != nil {
+		t.Errorf("reconcileDeletion returned: %+v", err)
+	}
+	// Calling it again (double-delete) must remain a no-op.
+	if err := bl.reconcileDeletion(ctx, &b); err != nil {
+		t.Errorf("reconcileDeletion returned: %+v", err)
+	}
+}
+
+func TestSyncBucketReissuesCreateForUnavailableBucket(t *testing.T) {
+	provisioner := "provisioner1"
+	bucketName := "bucket1"
+	called := false
+	mpc := struct{ fakespec.MockProvisionerClient }{}
+	mpc.CreateBucket = func(ctx context.Context, in *osspec.ProvisionerCreateBucketRequest, opts ...grpc.CallOption) (*osspec.ProvisionerCreateBucketResponse, error) {
+		called = true
+		return &osspec.ProvisionerCreateBucketResponse{}, nil
+	}
+
+	b := &v1alpha1.Bucket{
+		ObjectMeta: metav1.ObjectMeta{Name: bucketName},
+		Spec: v1alpha1.BucketSpec{
+			BucketRequest: &v1alpha1.ObjectReference{Name: bucketName},
+			Provisioner:   provisioner,
+			Protocol: v1alpha1.Protocol{
+				RequestedProtocol: v1alpha1.RequestedProtocol{Name: v1alpha1.ProtocolNameS3},
+			},
+		},
+	}
+	b.Spec.Protocol.S3 = &v1alpha1.S3Protocol{}
+
+	client := fakebucketclientset.NewSimpleClientset(b)
+	bl := bucketListener{
+		provisionerName:   provisioner,
+		provisionerClient: &mpc,
+		bucketClient:      client,
+		bucketIndexer:     newBucketIndexer(t, b),
+	}
+
+	if err := bl.syncBucket(context.TODO(), b); err != nil {
+		t.Errorf("syncBucket returned: %+v", err)
+	}
+	if !called {
+		t.Errorf("expected ProvisionerCreateBucket to be re-issued for a bucket that was never marked available")
+	}
+}
+
+func TestSyncBucketDoesNotRecreateAvailableBucket(t *testing.T) {
+	provisioner := "provisioner1"
+	bucketName := "bucket1"
+	mpc := struct{ fakespec.MockProvisionerClient }{}
+	mpc.CreateBucket = func(ctx context.Context, in *osspec.ProvisionerCreateBucketRequest, opts ...grpc.CallOption) (*osspec.ProvisionerCreateBucketResponse, error) {
+		t.Errorf("grpc client called")
+		return nil, nil
+	}
+
+	b := &v1alpha1.Bucket{
+		ObjectMeta: metav1.ObjectMeta{Name: bucketName},
+		Spec: v1alpha1.BucketSpec{
+			BucketRequest: &v1alpha1.ObjectReference{Name: bucketName},
+			Provisioner:   provisioner,
+		},
+		Status: v1alpha1.BucketStatus{BucketAvailable: true},
+	}
+
+	bl := bucketListener{
+		provisionerName:   provisioner,
+		provisionerClient: &mpc,
+		bucketIndexer:     newBucketIndexer(t, b),
+	}
+
+	if err := bl.syncBucket(context.TODO(), b); err != nil {
+		t.Errorf("syncBucket returned: %+v", err)
+	}
+}
+
+func TestSyncBucketPushesTagsOnceForAlreadyAvailableBucketWithNoAppliedRecord(t *testing.T) {
+	provisioner := "provisioner1"
+	bucketName := "bucket1"
+	var gotTags map[string]string
+	mpc := struct{ fakespec.MockProvisionerClient }{}
+	mpc.SetBucketTagging = func(ctx context.Context, in *osspec.ProvisionerSetBucketTaggingRequest, opts ...grpc.CallOption) (*osspec.ProvisionerSetBucketTaggingResponse, error) {
+		gotTags = in.Tags
+		return &osspec.ProvisionerSetBucketTaggingResponse{}, nil
+	}
+
+	b := &v1alpha1.Bucket{
+		ObjectMeta: metav1.ObjectMeta{Name: bucketName},
+		Spec: v1alpha1.BucketSpec{
+			BucketRequest: &v1alpha1.ObjectReference{Name: bucketName},
+			Provisioner:   provisioner,
+			Tags:          map[string]string{"env": "prod"},
+		},
+		Status: v1alpha1.BucketStatus{BucketAvailable: true},
+	}
+
+	client := fakebucketclientset.NewSimpleClientset(b)
+	bl := bucketListener{
+		provisionerName:   provisioner,
+		provisionerClient: &mpc,
+		bucketClient:      client,
+		bucketIndexer:     newBucketIndexer(t, b),
+	}
+
+	// No bl.applied entry at all for this bucket - e.g. a freshly started
+	// process walking a bucket it was never notified about via Add/Update.
+	// Diffing the live spec against itself here would wrongly look settled;
+	// syncBucket must instead diff against an empty baseline so real tags
+	// still get pushed at least once.
+	if err := bl.syncBucket(context.TODO(), b); err != nil {
+		t.Errorf("syncBucket returned: %+v", err)
+	}
+	if !reflect.DeepEqual(gotTags, map[string]string{"env": "prod"}) {
+		t.Errorf("expected resync with no prior applied record to push tags %v, got %v", b.Spec.Tags, gotTags)
+	}
+}
+
+func TestSyncBucketCorrectsTagDriftForAvailableBucket(t *testing.T) {
+	provisioner := "provisioner1"
+	bucketName := "bucket1"
+	var gotTags map[string]string
+	mpc := struct{ fakespec.MockProvisionerClient }{}
+	mpc.SetBucketTagging = func(ctx context.Context, in *osspec.ProvisionerSetBucketTaggingRequest, opts ...grpc.CallOption) (*osspec.ProvisionerSetBucketTaggingResponse, error) {
+		gotTags = in.Tags
+		return &osspec.ProvisionerSetBucketTaggingResponse{}, nil
+	}
+
+	b := &v1alpha1.Bucket{
+		ObjectMeta: metav1.ObjectMeta{Name: bucketName},
+		Spec: v1alpha1.BucketSpec{
+			BucketRequest: &v1alpha1.ObjectReference{Name: bucketName},
+			Provisioner:   provisioner,
+			Tags:          map[string]string{"env": "prod"},
+			Protocol: v1alpha1.Protocol{
+				RequestedProtocol: v1alpha1.RequestedProtocol{Name: v1alpha1.ProtocolNameS3},
+			},
+		},
+		Status: v1alpha1.BucketStatus{BucketAvailable: true},
+	}
+	b.Spec.Protocol.S3 = &v1alpha1.S3Protocol{}
+
+	client := fakebucketclientset.NewSimpleClientset(b)
+	bl := bucketListener{
+		provisionerName:   provisioner,
+		provisionerClient: &mpc,
+		bucketClient:      client,
+		bucketIndexer:     newBucketIndexer(t, b),
+	}
+
+	// Simulate a bucket already marked available whose tags never actually
+	// reached the provisioner - e.g. an earlier updateBucket RPC failed, so
+	// recordApplied never ran for the new tags. lastApplied records the stale
+	// tags the provisioner is
+	// assumed to still have; syncBucket must diff against that, not against
+	// the live object itself, to notice the drift.
+	bl.applied = map[string]appliedBucketState{
+		bucketName: {tags: map[string]string{"env": "staging"}, protocol: b.Spec.Protocol},
+	}
+
+	if err := bl.syncBucket(context.TODO(), b); err != nil {
+		t.Errorf("syncBucket returned: %+v", err)
+	}
+	if !reflect.DeepEqual(gotTags, map[string]string{"env": "prod"}) {
+		t.Errorf("expected resync to push drifted tags %v to the provisioner, got %v", b.Spec.Tags, gotTags)
+	}
+	applied, ok := bl.lastApplied(bucketName)
+	if !ok || !reflect.DeepEqual(applied.tags, b.Spec.Tags) {
+		t.Errorf("expected lastApplied to be updated to %v after a successful resync, got %+v (ok=%v)", b.Spec.Tags, applied, ok)
+	}
+}
+
+func TestSyncBucketSkipsOtherProvisioners(t *testing.T) {
+	bucketName := "bucket1"
+	mpc := struct{ fakespec.MockProvisionerClient }{}
+	mpc.CreateBucket = func(ctx context.Context, in *osspec.ProvisionerCreateBucketRequest, opts ...grpc.CallOption) (*osspec.ProvisionerCreateBucketResponse, error) {
+		t.Errorf("grpc client called")
+		return nil, nil
+	}
+
+	b := &v1alpha1.Bucket{
+		ObjectMeta: metav1.ObjectMeta{Name: bucketName},
+		Spec: v1alpha1.BucketSpec{
+			BucketRequest: &v1alpha1.ObjectReference{Name: bucketName},
+			Provisioner:   "provisioner2",
+		},
+	}
+
+	bl := bucketListener{
+		provisionerName:   "provisioner1",
+		provisionerClient: &mpc,
+		bucketIndexer:     newBucketIndexer(t, b),
+	}
+
+	if err := bl.syncBucket(context.TODO(), b); err != nil {
+		t.Errorf("syncBucket returned: %+v", err)
+	}
+}
+
+func TestBucketsForProvisionerIsIndexed(t *testing.T) {
+	mine1 := &v1alpha1.Bucket{
+		ObjectMeta: metav1.ObjectMeta{Name: "mine1"},
+		Spec:       v1alpha1.BucketSpec{Provisioner: "provisioner1"},
+	}
+	mine2 := &v1alpha1.Bucket{
+		ObjectMeta: metav1.ObjectMeta{Name: "mine2"},
+		Spec:       v1alpha1.BucketSpec{Provisioner: "provisioner1"},
+	}
+	other := &v1alpha1.Bucket{
+		ObjectMeta: metav1.ObjectMeta{Name: "other"},
+		Spec:       v1alpha1.BucketSpec{Provisioner: "provisioner2"},
+	}
+
+	bl := bucketListener{
+		provisionerName: "provisioner1",
+		bucketIndexer:   newBucketIndexer(t, mine1, mine2, other),
+	}
+
+	buckets, err := bl.bucketsForProvisioner()
+	if err != nil {
+		t.Fatalf("bucketsForProvisioner returned: %+v", err)
+	}
+	if len(buckets) != 2 {
+		t.Errorf("expected 2 buckets, got %d", len(buckets))
+	}
+	for _, b := range buckets {
+		if b.Spec.Provisioner != "provisioner1" {
+			t.Errorf("unexpected bucket %s for provisioner %s", b.Name, b.Spec.Provisioner)
+		}
 	}
 }
 
@@ -366,6 +1466,7 @@ func TestDeleteInvalidProtocol(t *testing.T) {
 			},
 		},
 	}
+	b.Finalizers = []string{bucketProtectionFinalizer}
 
 	ctx := context.TODO()
 	err := bl.Delete(ctx, &b)